@@ -0,0 +1,65 @@
+package main
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+)
+
+// AuditEvent is one immutable record of a bind/unbind/config-mutation
+// action, written as a single JSON line so operators can ship the stream
+// to a SIEM without a custom parser.
+type AuditEvent struct {
+	Time   time.Time `json:"time"`
+	Peer   string    `json:"peer,omitempty"`
+	BusID  string    `json:"busid,omitempty"`
+	Info   string    `json:"info,omitempty"`
+	Action string    `json:"action"`
+	Result string    `json:"result"`
+	Error  string    `json:"error,omitempty"`
+}
+
+// AuditLogger appends AuditEvents as JSON lines to a rotating file,
+// independent of the human-readable console logger on Server.
+type AuditLogger struct {
+	mu     sync.Mutex
+	writer io.WriteCloser
+	enc    *json.Encoder
+}
+
+// nopWriteCloser adapts an io.Writer into an io.WriteCloser whose Close is a
+// no-op, for the discard case where there is no underlying file to release.
+type nopWriteCloser struct{ io.Writer }
+
+func (nopWriteCloser) Close() error { return nil }
+
+// NewAuditLogger builds an AuditLogger from the log_* config keys. When
+// cfg.LogFile is empty, audit events are silently discarded so usbctl
+// keeps working without an explicit opt-in to the audit trail.
+func NewAuditLogger(cfg *Config) (*AuditLogger, error) {
+	if cfg.LogFile == "" {
+		return &AuditLogger{writer: nopWriteCloser{io.Discard}, enc: json.NewEncoder(io.Discard)}, nil
+	}
+
+	rotator, err := newRotatingWriter(cfg.LogFile, cfg.LogMaxSizeMB, cfg.LogMaxBackups, cfg.LogMaxAgeDays, cfg.LogCompress)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditLogger{writer: rotator, enc: json.NewEncoder(rotator)}, nil
+}
+
+// Log appends event to the audit stream.
+func (a *AuditLogger) Log(event AuditEvent) {
+	event.Time = time.Now()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	a.enc.Encode(event)
+}
+
+// Close releases the underlying log file.
+func (a *AuditLogger) Close() error {
+	return a.writer.Close()
+}
@@ -0,0 +1,75 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"runtime"
+)
+
+// Backend abstracts the platform- and transport-specific mechanics of
+// exporting and consuming USB/IP devices. It exists so that execCommand,
+// listDevices, bindDevice, unbindDevice, and isDeviceBound no longer need to
+// branch on runtime.GOOS: each supported transport implements this
+// interface and registers itself by name, and usbctl selects one at
+// startup via the backend= config key or the --backend flag.
+type Backend interface {
+	// Name returns the backend's registry key, e.g. "usbip" or "usbipd".
+	Name() string
+
+	// List returns the USB devices visible on the local host, including
+	// their current bound state.
+	List(ctx context.Context) ([]Device, error)
+
+	// Bind exports busid so a remote host can attach it.
+	Bind(ctx context.Context, busid string) error
+
+	// Unbind stops exporting busid.
+	Unbind(ctx context.Context, busid string) error
+
+	// IsBound reports whether busid is currently exported.
+	IsBound(busid string) bool
+
+	// Attach imports busid from host and returns the local port it was
+	// attached to.
+	Attach(ctx context.Context, host, busid string) (port int, err error)
+
+	// Detach releases a previously attached port.
+	Detach(ctx context.Context, port int) error
+
+	// ListRemote returns the USB devices a peer host is exporting, so a
+	// client can browse them before attaching.
+	ListRemote(ctx context.Context, host string) ([]RemoteDevice, error)
+}
+
+// backendFactories holds the registry of backend constructors, keyed by
+// name. Backends register themselves from an init() function in their own
+// file so that new transports can be added without touching this file.
+var backendFactories = map[string]func() Backend{}
+
+// RegisterBackend adds a backend constructor to the registry. It panics on
+// a duplicate name, since that indicates two backends were compiled in
+// with the same identity.
+func RegisterBackend(name string, factory func() Backend) {
+	if _, exists := backendFactories[name]; exists {
+		panic(fmt.Sprintf("backend already registered: %s", name))
+	}
+	backendFactories[name] = factory
+}
+
+// NewBackend constructs the backend registered under name.
+func NewBackend(name string) (Backend, error) {
+	factory, ok := backendFactories[name]
+	if !ok {
+		return nil, fmt.Errorf("unknown backend: %s", name)
+	}
+	return factory(), nil
+}
+
+// defaultBackendName returns the backend to use when none was configured,
+// based on the host platform.
+func defaultBackendName() string {
+	if runtime.GOOS == "windows" {
+		return "usbipd"
+	}
+	return "usbip"
+}
@@ -0,0 +1,89 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+func init() {
+	RegisterBackend("native", func() Backend { return &nativeBackend{} })
+}
+
+const usbipHostDriverPath = "/sys/bus/usb/drivers/usbip-host"
+
+// nativeBackend exports devices by writing directly to the usbip-host
+// driver's bind/unbind sysfs attributes instead of shelling out to the
+// usbip CLI. It still uses `usbip list -l` to enumerate devices, since
+// usbip-utils is the only thing that knows how to read and format USB
+// descriptors, but the bind/unbind/isBound hot path avoids fork/exec
+// entirely.
+type nativeBackend struct{}
+
+func (b *nativeBackend) Name() string { return "native" }
+
+func (b *nativeBackend) List(ctx context.Context) ([]Device, error) {
+	output, err := execCommandContext(ctx, "usbip", "list", "-l")
+	if err != nil {
+		return nil, err
+	}
+	return parseUsbipList(output, b.IsBound), nil
+}
+
+func (b *nativeBackend) Bind(_ context.Context, busid string) error {
+	if !validateBusID(busid) {
+		return fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	if err := writeDriverAttr("bind", busid); err != nil {
+		return fmt.Errorf("failed to bind device: %w", err)
+	}
+	return nil
+}
+
+func (b *nativeBackend) Unbind(_ context.Context, busid string) error {
+	if !validateBusID(busid) {
+		return fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	if err := writeDriverAttr("unbind", busid); err != nil {
+		return fmt.Errorf("failed to unbind device: %w", err)
+	}
+	return nil
+}
+
+func (b *nativeBackend) IsBound(busid string) bool {
+	_, err := os.Stat(filepath.Join(usbipHostDriverPath, busid))
+	return err == nil
+}
+
+func (b *nativeBackend) Attach(ctx context.Context, host, busid string) (int, error) {
+	// Importing a remote device still requires the usbip kernel module's
+	// userspace helper to negotiate the protocol handshake, so attach falls
+	// back to the usbip CLI rather than duplicating its vhci-hcd wiring.
+	return (&usbipBackend{}).Attach(ctx, host, busid)
+}
+
+func (b *nativeBackend) Detach(ctx context.Context, port int) error {
+	return (&usbipBackend{}).Detach(ctx, port)
+}
+
+func (b *nativeBackend) ListRemote(ctx context.Context, host string) ([]RemoteDevice, error) {
+	return (&usbipBackend{}).ListRemote(ctx, host)
+}
+
+// writeDriverAttr writes busid to the usbip-host driver's bind or unbind
+// attribute, the same operation `usbip bind`/`usbip unbind` perform
+// internally, without spawning a subprocess.
+func writeDriverAttr(attr, busid string) error {
+	path := filepath.Join(usbipHostDriverPath, attr)
+	f, err := os.OpenFile(path, os.O_WRONLY, 0)
+	if err != nil {
+		return fmt.Errorf("open %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if _, err := f.WriteString(busid); err != nil {
+		return fmt.Errorf("write %s to %s: %w", busid, path, err)
+	}
+	return nil
+}
@@ -0,0 +1,165 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterBackend("usbip", func() Backend { return &usbipBackend{} })
+}
+
+// usbipBackend drives the Linux usbip CLI (usbip-utils). It is the
+// original implementation usbctl shipped with, unchanged apart from
+// living behind the Backend interface.
+type usbipBackend struct{}
+
+func (b *usbipBackend) Name() string { return "usbip" }
+
+func (b *usbipBackend) List(ctx context.Context) ([]Device, error) {
+	output, err := execCommandContext(ctx, "usbip", "list", "-l")
+	if err != nil {
+		return nil, err
+	}
+	return parseUsbipList(output, b.IsBound), nil
+}
+
+func (b *usbipBackend) Bind(ctx context.Context, busid string) error {
+	if !validateBusID(busid) {
+		return fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	_, err := execCommandContext(ctx, "usbip", "bind", "-b", busid)
+	if err != nil {
+		return fmt.Errorf("failed to bind device: %w", err)
+	}
+	return nil
+}
+
+func (b *usbipBackend) Unbind(ctx context.Context, busid string) error {
+	if !validateBusID(busid) {
+		return fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	_, err := execCommandContext(ctx, "usbip", "unbind", "-b", busid)
+	if err != nil {
+		return fmt.Errorf("failed to unbind device: %w", err)
+	}
+	return nil
+}
+
+func (b *usbipBackend) IsBound(busid string) bool {
+	path := filepath.Join("/sys/bus/usb/drivers/usbip-host", busid)
+	_, err := os.Stat(path)
+	return err == nil
+}
+
+func (b *usbipBackend) Attach(ctx context.Context, host, busid string) (int, error) {
+	if !validateBusID(busid) {
+		return 0, fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	if _, err := execCommandContext(ctx, "usbip", "attach", "-r", host, "-b", busid); err != nil {
+		return 0, fmt.Errorf("failed to attach device: %w", err)
+	}
+	return findAttachedPort(ctx, host, busid)
+}
+
+func (b *usbipBackend) Detach(ctx context.Context, port int) error {
+	_, err := execCommandContext(ctx, "usbip", "detach", "-p", strconv.Itoa(port))
+	if err != nil {
+		return fmt.Errorf("failed to detach port %d: %w", port, err)
+	}
+	return nil
+}
+
+func (b *usbipBackend) ListRemote(ctx context.Context, host string) ([]RemoteDevice, error) {
+	output, err := execCommandContext(ctx, "usbip", "list", "-r", host)
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote devices on %s: %w", host, err)
+	}
+
+	var devices []RemoteDevice
+	for _, d := range parseUsbipList(output, func(string) bool { return false }) {
+		devices = append(devices, RemoteDevice{Host: host, BusID: d.BusID, Info: d.Info})
+	}
+	return devices, nil
+}
+
+// findAttachedPort cross-references `usbip port` output against host and
+// busid to recover the local port number that attach just created. usbip
+// attach does not print the port itself, so this is the only way to learn
+// it synchronously.
+func findAttachedPort(ctx context.Context, host, busid string) (int, error) {
+	output, err := execCommandContext(ctx, "usbip", "port")
+	if err != nil {
+		return 0, fmt.Errorf("failed to query attached ports: %w", err)
+	}
+
+	var pendingPort int
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "Port ") {
+			fields := strings.FieldsFunc(trimmed, func(r rune) bool {
+				return r == ' ' || r == ':'
+			})
+			if len(fields) >= 2 {
+				if p, err := strconv.Atoi(fields[1]); err == nil {
+					pendingPort = p
+				}
+			}
+			continue
+		}
+		if strings.Contains(trimmed, host) && strings.Contains(trimmed, busid) {
+			return pendingPort, nil
+		}
+	}
+
+	return 0, fmt.Errorf("could not determine attached port for %s on %s", busid, host)
+}
+
+// parseUsbipList parses `usbip list -l` output into Devices, delegating
+// bound-state lookup to isBound so callers can plug in whichever
+// mechanism (sysfs, CLI, ...) matches their backend.
+func parseUsbipList(output string, isBound func(string) bool) []Device {
+	var devices []Device
+	var current *Device
+
+	for _, rawLine := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(rawLine)
+
+		if trimmed == "" {
+			continue
+		}
+
+		if strings.HasPrefix(trimmed, "- busid") || strings.HasPrefix(trimmed, "BUSID") {
+			if current != nil {
+				devices = append(devices, *current)
+			}
+
+			current = &Device{}
+
+			parts := strings.Fields(trimmed)
+			for i, part := range parts {
+				if (part == "busid" || part == "BUSID") && i+1 < len(parts) {
+					busid := strings.TrimRight(parts[i+1], ":()")
+					current.BusID = busid
+					current.Bound = isBound(busid)
+					break
+				}
+			}
+		} else if current != nil && (strings.HasPrefix(rawLine, " ") || strings.HasPrefix(rawLine, "\t")) {
+			if current.Info != "" {
+				current.Info += " "
+			}
+			current.Info += trimmed
+		}
+	}
+
+	if current != nil {
+		devices = append(devices, *current)
+	}
+
+	return devices
+}
@@ -0,0 +1,151 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"regexp"
+	"strings"
+	"sync"
+)
+
+func init() {
+	RegisterBackend("usbipd", func() Backend { return &usbipdBackend{} })
+}
+
+// usbipdBackend drives usbipd-win (https://github.com/dorssel/usbipd-win) to
+// export local USB devices for attachment into a WSL distro. Unlike Linux
+// usbip, "bind" in usbipd-win terms shares a device and "attach" is a
+// separate step that imports it into a running WSL instance, so Bind/Unbind
+// here map to `usbipd bind`/`usbipd unbind` rather than the wsl subcommand
+// the original stub used.
+type usbipdBackend struct {
+	mu       sync.Mutex
+	nextPort int
+	ports    map[int]string // port -> busid, for attached devices
+}
+
+func (b *usbipdBackend) Name() string { return "usbipd" }
+
+func (b *usbipdBackend) List(ctx context.Context) ([]Device, error) {
+	output, err := execCommandContext(ctx, "usbipd", "wsl", "list")
+	if err != nil {
+		return nil, err
+	}
+	return parseUsbipdList(output), nil
+}
+
+func (b *usbipdBackend) Bind(ctx context.Context, busid string) error {
+	if !validateBusID(busid) {
+		return fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	if _, err := execCommandContext(ctx, "usbipd", "bind", "--busid", busid); err != nil {
+		return fmt.Errorf("failed to bind device: %w", err)
+	}
+	return nil
+}
+
+func (b *usbipdBackend) Unbind(ctx context.Context, busid string) error {
+	if !validateBusID(busid) {
+		return fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	if _, err := execCommandContext(ctx, "usbipd", "unbind", "--busid", busid); err != nil {
+		return fmt.Errorf("failed to unbind device: %w", err)
+	}
+	return nil
+}
+
+// IsBound reports the shared/attached state by re-listing, since usbipd-win
+// keeps no local sysfs-style marker to stat.
+func (b *usbipdBackend) IsBound(busid string) bool {
+	devices, err := b.List(context.Background())
+	if err != nil {
+		return false
+	}
+	for _, d := range devices {
+		if d.BusID == busid {
+			return d.Bound
+		}
+	}
+	return false
+}
+
+// Attach is not supported: `usbipd wsl attach` shares a *locally connected*
+// Windows device into WSL, which has nothing to do with importing busid
+// from a remote host as the Backend.Attach contract requires. Since this
+// backend's ListRemote can never discover a peer's devices in the first
+// place, there is no host to honor here, so fail clearly instead of quietly
+// reattaching whatever local device happens to have that busid.
+func (b *usbipdBackend) Attach(ctx context.Context, host, busid string) (int, error) {
+	return 0, fmt.Errorf("usbipd backend does not support attaching from a remote host")
+}
+
+func (b *usbipdBackend) Detach(ctx context.Context, port int) error {
+	b.mu.Lock()
+	busid, ok := b.ports[port]
+	b.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("no attached device on port %d", port)
+	}
+
+	if _, err := execCommandContext(ctx, "usbipd", "wsl", "detach", "--busid", busid); err != nil {
+		return fmt.Errorf("failed to detach port %d: %w", port, err)
+	}
+
+	b.mu.Lock()
+	delete(b.ports, port)
+	b.mu.Unlock()
+	return nil
+}
+
+// ListRemote is not supported: usbipd-win only shares devices into the
+// local machine's own WSL instances, it has no concept of browsing a peer
+// usbipd server over the network the way Linux usbip list -r does.
+func (b *usbipdBackend) ListRemote(ctx context.Context, host string) ([]RemoteDevice, error) {
+	return nil, fmt.Errorf("usbipd backend does not support listing remote hosts")
+}
+
+// parseUsbipdList parses the table printed by `usbipd wsl list`:
+//
+//	BUSID  VID:PID    DEVICE                                    STATE
+//	1-4    046d:c52b  Logitech USB Receiver                      Not shared
+//	2-1    0483:374e  ST-Link Debug, USB Mass Storage Device      Attached - Ubuntu
+//
+// A device is considered bound when its state is anything other than
+// "Not shared" (i.e. "Shared" or "Attached - <distro>").
+//
+// usbipdStateSuffixPattern matches the trailing STATE column so it can be
+// stripped from the DEVICE description: "Attached - <distro>" carries a
+// variable distro name, so a literal suffix trim only works for "Shared"
+// and "Not shared".
+var usbipdStateSuffixPattern = regexp.MustCompile(`\s+(Not shared|Shared|Attached(?:\s*-\s*\S+)?)\s*$`)
+
+func parseUsbipdList(output string) []Device {
+	var devices []Device
+
+	lines := strings.Split(output, "\n")
+	for _, line := range lines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" || strings.HasPrefix(trimmed, "BUSID") {
+			continue
+		}
+
+		fields := strings.Fields(trimmed)
+		if len(fields) < 3 {
+			continue
+		}
+
+		busid := fields[0]
+		if !validateBusID(busid) {
+			continue
+		}
+
+		bound := !strings.Contains(trimmed, "Not shared")
+
+		info := strings.TrimSpace(strings.TrimPrefix(trimmed, busid))
+		info = strings.TrimSpace(usbipdStateSuffixPattern.ReplaceAllString(info, ""))
+
+		devices = append(devices, Device{BusID: busid, Info: info, Bound: bound})
+	}
+
+	return devices
+}
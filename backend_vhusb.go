@@ -0,0 +1,139 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+func init() {
+	RegisterBackend("vhusb", func() Backend { return &vhusbBackend{} })
+}
+
+// vhusbBackend drives the VirtualHere USB client/server CLIs
+// (vhclientx86_64 / vhusbdx86_64), a proprietary USB-over-TCP transport
+// that some users run instead of, or alongside, kernel usbip. Local
+// export state is read by asking the VirtualHere server which of its
+// devices are currently in use by a client; remote attach/detach goes
+// through the client's interactive-command pipe, invoked here in
+// one-shot mode via `-r`.
+type vhusbBackend struct{}
+
+func (b *vhusbBackend) Name() string { return "vhusb" }
+
+func (b *vhusbBackend) List(ctx context.Context) ([]Device, error) {
+	output, err := execCommandContext(ctx, "vhusbdx86_64", "-t", "LIST")
+	if err != nil {
+		return nil, err
+	}
+	return parseVhusbList(output), nil
+}
+
+func (b *vhusbBackend) Bind(ctx context.Context, busid string) error {
+	if !validateBusID(busid) {
+		return fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	if _, err := execCommandContext(ctx, "vhusbdx86_64", "-t", fmt.Sprintf("SHARE,%s", busid)); err != nil {
+		return fmt.Errorf("failed to bind device: %w", err)
+	}
+	return nil
+}
+
+func (b *vhusbBackend) Unbind(ctx context.Context, busid string) error {
+	if !validateBusID(busid) {
+		return fmt.Errorf("invalid bus ID: %s", busid)
+	}
+	if _, err := execCommandContext(ctx, "vhusbdx86_64", "-t", fmt.Sprintf("UNSHARE,%s", busid)); err != nil {
+		return fmt.Errorf("failed to unbind device: %w", err)
+	}
+	return nil
+}
+
+func (b *vhusbBackend) IsBound(busid string) bool {
+	devices, err := b.List(context.Background())
+	if err != nil {
+		return false
+	}
+	for _, d := range devices {
+		if d.BusID == busid {
+			return d.Bound
+		}
+	}
+	return false
+}
+
+func (b *vhusbBackend) Attach(ctx context.Context, host, busid string) (int, error) {
+	if !validateBusID(busid) {
+		return 0, fmt.Errorf("invalid bus ID: %s", busid)
+	}
+
+	addr := fmt.Sprintf("%s.%s", host, busid)
+	output, err := execCommandContext(ctx, "vhclientx86_64", "-r", fmt.Sprintf("USE,%s", addr))
+	if err != nil {
+		return 0, fmt.Errorf("failed to attach device: %w", err)
+	}
+
+	port, err := parseVhusbPort(output)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse attach result: %w", err)
+	}
+	return port, nil
+}
+
+func (b *vhusbBackend) Detach(ctx context.Context, port int) error {
+	if _, err := execCommandContext(ctx, "vhclientx86_64", "-r", fmt.Sprintf("STOPUSE,%d", port)); err != nil {
+		return fmt.Errorf("failed to detach port %d: %w", port, err)
+	}
+	return nil
+}
+
+func (b *vhusbBackend) ListRemote(ctx context.Context, host string) ([]RemoteDevice, error) {
+	output, err := execCommandContext(ctx, "vhclientx86_64", "-r", fmt.Sprintf("LIST,%s", host))
+	if err != nil {
+		return nil, fmt.Errorf("failed to list remote devices on %s: %w", host, err)
+	}
+
+	var devices []RemoteDevice
+	for _, d := range parseVhusbList(output) {
+		devices = append(devices, RemoteDevice{Host: host, BusID: d.BusID, Info: d.Info})
+	}
+	return devices, nil
+}
+
+// parseVhusbList parses VirtualHere's `-t LIST` output, one device per
+// line as "BUSID\tDESCRIPTION\tIN_USE=0|1".
+func parseVhusbList(output string) []Device {
+	var devices []Device
+
+	for _, line := range strings.Split(output, "\n") {
+		trimmed := strings.TrimSpace(line)
+		if trimmed == "" {
+			continue
+		}
+
+		fields := strings.Split(trimmed, "\t")
+		if len(fields) < 3 {
+			continue
+		}
+
+		devices = append(devices, Device{
+			BusID: strings.TrimSpace(fields[0]),
+			Info:  strings.TrimSpace(fields[1]),
+			Bound: strings.TrimSpace(fields[2]) == "IN_USE=1",
+		})
+	}
+
+	return devices
+}
+
+// parseVhusbPort extracts the local port number VirtualHere assigned from
+// a "PORT=N" response line.
+func parseVhusbPort(output string) (int, error) {
+	for _, line := range strings.Split(output, "\n") {
+		if v, ok := strings.CutPrefix(strings.TrimSpace(line), "PORT="); ok {
+			return strconv.Atoi(v)
+		}
+	}
+	return 0, fmt.Errorf("no PORT= line in response")
+}
@@ -0,0 +1,53 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+)
+
+// identityDir returns the directory identity keypairs and other
+// per-installation state live in: alongside the config file.
+func identityDir(cfg *Config) string {
+	return filepath.Dir(cfg.ConfigPath)
+}
+
+// runIDCommand implements `usbctl id`: print this installation's device ID,
+// generating its identity keypair on first run if necessary.
+func runIDCommand(cfg *Config) error {
+	identity, err := LoadOrCreateIdentity(identityDir(cfg))
+	if err != nil {
+		return fmt.Errorf("failed to load identity: %w", err)
+	}
+
+	fmt.Println(identity.DeviceID)
+	return nil
+}
+
+// runTrustCommand implements `usbctl trust <id>`: add a peer device ID to
+// the trusted_peer allow-list so it can be accepted over mTLS.
+func runTrustCommand(cfg *Config, peerID string) error {
+	for _, existing := range cfg.TrustedPeers {
+		if existing == peerID {
+			fmt.Printf("%s is already trusted\n", peerID)
+			return nil
+		}
+	}
+
+	cfg.TrustedPeers = append(cfg.TrustedPeers, peerID)
+
+	server := NewServer(cfg)
+	err := server.saveConfig()
+	server.audit.Log(AuditEvent{
+		Peer:   peerID,
+		Info:   "trusted_peer",
+		Action: "trust_add",
+		Result: auditResult(err),
+		Error:  errString(err),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to save config: %w", err)
+	}
+
+	fmt.Printf("Trusted %s\n", peerID)
+	return nil
+}
@@ -0,0 +1,131 @@
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/sha256"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base32"
+	"encoding/pem"
+	"fmt"
+	"math/big"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+const (
+	identityCertFile = "identity-cert.pem"
+	identityKeyFile  = "identity-key.pem"
+	identityCertDays = 20 * 365 // long-lived, matches the "no CA, no passwords" pairing model
+)
+
+// Identity is usbctl's self-generated, long-lived device identity: a
+// self-signed X.509 keypair plus the short fingerprint derived from it.
+// There is no CA; two usbctl instances trust each other purely by an
+// operator pasting the other side's DeviceID into `trusted_peer=`, the
+// same pairing model syncthing uses for its device IDs.
+type Identity struct {
+	Certificate tls.Certificate
+	DeviceID    string
+}
+
+// LoadOrCreateIdentity loads the identity keypair from dir, generating and
+// persisting a new one on first launch.
+func LoadOrCreateIdentity(dir string) (*Identity, error) {
+	certPath := filepath.Join(dir, identityCertFile)
+	keyPath := filepath.Join(dir, identityKeyFile)
+
+	certPEM, certErr := os.ReadFile(certPath)
+	keyPEM, keyErr := os.ReadFile(keyPath)
+	if certErr == nil && keyErr == nil {
+		return identityFromPEM(certPEM, keyPEM)
+	}
+
+	certPEM, keyPEM, err := generateIdentityPEM()
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate identity: %w", err)
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("failed to create identity directory: %w", err)
+	}
+	if err := os.WriteFile(certPath, certPEM, 0644); err != nil {
+		return nil, fmt.Errorf("failed to write identity cert: %w", err)
+	}
+	if err := os.WriteFile(keyPath, keyPEM, 0600); err != nil {
+		return nil, fmt.Errorf("failed to write identity key: %w", err)
+	}
+
+	return identityFromPEM(certPEM, keyPEM)
+}
+
+func identityFromPEM(certPEM, keyPEM []byte) (*Identity, error) {
+	cert, err := tls.X509KeyPair(certPEM, keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse identity keypair: %w", err)
+	}
+
+	return &Identity{Certificate: cert, DeviceID: DeviceIDFromCert(cert.Certificate[0])}, nil
+}
+
+// generateIdentityPEM creates a new ECDSA P-256 self-signed certificate and
+// returns it and its private key, both PEM-encoded.
+func generateIdentityPEM() (certPEM, keyPEM []byte, err error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, nil, err
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: serial,
+		Subject:      pkix.Name{CommonName: "usbctl"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().AddDate(0, 0, identityCertDays),
+		KeyUsage:     x509.KeyUsageDigitalSignature | x509.KeyUsageKeyEncipherment,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth, x509.ExtKeyUsageClientAuth},
+	}
+
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER})
+	return certPEM, keyPEM, nil
+}
+
+// DeviceIDFromCert derives a syncthing-style device ID from a DER-encoded
+// certificate: unpadded base32 of the full 32-byte SHA-256 digest (52
+// characters; the digest is not truncated), grouped into hyphen-separated
+// 7-character blocks, e.g. "AIR6LPZ-7K4PTTV-...".
+func DeviceIDFromCert(der []byte) string {
+	sum := sha256.Sum256(der)
+	encoded := base32.StdEncoding.WithPadding(base32.NoPadding).EncodeToString(sum[:])
+
+	var groups []string
+	for i := 0; i < len(encoded); i += 7 {
+		end := i + 7
+		if end > len(encoded) {
+			end = len(encoded)
+		}
+		groups = append(groups, encoded[i:end])
+	}
+
+	return strings.Join(groups, "-")
+}
@@ -0,0 +1,177 @@
+package main
+
+import (
+	"compress/gzip"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// rotatingWriter is a minimal, dependency-free stand-in for
+// gopkg.in/natefinch/lumberjack: it writes to a single file, and once that
+// file crosses maxSizeMB it is renamed aside (optionally gzip-compressed)
+// and a fresh file is opened in its place. maxBackups and maxAgeDays prune
+// old rotated files on each rotation.
+type rotatingWriter struct {
+	path       string
+	maxSizeMB  int
+	maxBackups int
+	maxAgeDays int
+	compress   bool
+
+	mu   sync.Mutex
+	file *os.File
+	size int64
+}
+
+func newRotatingWriter(path string, maxSizeMB, maxBackups, maxAgeDays int, compress bool) (*rotatingWriter, error) {
+	w := &rotatingWriter{
+		path:       path,
+		maxSizeMB:  maxSizeMB,
+		maxBackups: maxBackups,
+		maxAgeDays: maxAgeDays,
+		compress:   compress,
+	}
+	if err := w.openExisting(); err != nil {
+		return nil, err
+	}
+	return w, nil
+}
+
+func (w *rotatingWriter) openExisting() error {
+	if err := os.MkdirAll(filepath.Dir(w.path), 0755); err != nil {
+		return fmt.Errorf("failed to create log directory: %w", err)
+	}
+
+	f, err := os.OpenFile(w.path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open log file: %w", err)
+	}
+
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return fmt.Errorf("failed to stat log file: %w", err)
+	}
+
+	w.file = f
+	w.size = info.Size()
+	return nil
+}
+
+func (w *rotatingWriter) Write(p []byte) (int, error) {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+
+	if w.maxSizeMB > 0 && w.size+int64(len(p)) > int64(w.maxSizeMB)*1024*1024 {
+		if err := w.rotate(); err != nil {
+			return 0, err
+		}
+	}
+
+	n, err := w.file.Write(p)
+	w.size += int64(n)
+	return n, err
+}
+
+// rotate closes the current file, renames it aside with a timestamp
+// suffix, optionally compresses it, opens a fresh file in its place, and
+// prunes backups beyond maxBackups/maxAgeDays.
+func (w *rotatingWriter) rotate() error {
+	if err := w.file.Close(); err != nil {
+		return fmt.Errorf("failed to close log file for rotation: %w", err)
+	}
+
+	rotated := fmt.Sprintf("%s.%s", w.path, time.Now().Format("20060102T150405"))
+	if err := os.Rename(w.path, rotated); err != nil {
+		return fmt.Errorf("failed to rotate log file: %w", err)
+	}
+
+	if w.compress {
+		if err := compressFile(rotated); err != nil {
+			return fmt.Errorf("failed to compress rotated log: %w", err)
+		}
+	}
+
+	if err := w.openExisting(); err != nil {
+		return err
+	}
+
+	w.pruneBackups()
+	return nil
+}
+
+func compressFile(path string) error {
+	src, err := os.Open(path)
+	if err != nil {
+		return err
+	}
+	defer src.Close()
+
+	dst, err := os.Create(path + ".gz")
+	if err != nil {
+		return err
+	}
+	defer dst.Close()
+
+	gz := gzip.NewWriter(dst)
+	if _, err := io.Copy(gz, src); err != nil {
+		gz.Close()
+		return err
+	}
+	if err := gz.Close(); err != nil {
+		return err
+	}
+
+	return os.Remove(path)
+}
+
+// pruneBackups removes rotated log files beyond maxBackups (newest kept)
+// and older than maxAgeDays, mirroring lumberjack's retention policy.
+func (w *rotatingWriter) pruneBackups() {
+	dir := filepath.Dir(w.path)
+	base := filepath.Base(w.path)
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return
+	}
+
+	var backups []os.DirEntry
+	for _, e := range entries {
+		if e.IsDir() || !strings.HasPrefix(e.Name(), base+".") {
+			continue
+		}
+		backups = append(backups, e)
+	}
+
+	sort.Slice(backups, func(i, j int) bool {
+		return backups[i].Name() > backups[j].Name() // newest first
+	})
+
+	cutoff := time.Now().AddDate(0, 0, -w.maxAgeDays)
+	for i, e := range backups {
+		info, err := e.Info()
+		if err != nil {
+			continue
+		}
+
+		tooOld := w.maxAgeDays > 0 && info.ModTime().Before(cutoff)
+		tooMany := w.maxBackups > 0 && i >= w.maxBackups
+
+		if tooOld || tooMany {
+			os.Remove(filepath.Join(dir, e.Name()))
+		}
+	}
+}
+
+func (w *rotatingWriter) Close() error {
+	w.mu.Lock()
+	defer w.mu.Unlock()
+	return w.file.Close()
+}
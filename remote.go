@@ -0,0 +1,163 @@
+package main
+
+import (
+	"context"
+	"fmt"
+)
+
+// RemoteDevice describes a USB device exported by a peer usbctl/usbip
+// server, as discovered by ListRemote.
+type RemoteDevice struct {
+	Host  string `json:"host"`
+	BusID string `json:"busid"`
+	Info  string `json:"info"`
+}
+
+// Attachment is a device currently imported from a remote host, keyed by
+// the local port it was attached to.
+type Attachment struct {
+	Port  int    `json:"port"`
+	Host  string `json:"host"`
+	BusID string `json:"busid"`
+}
+
+// attachRemote imports busid from host via the configured backend, records
+// the resulting port in the live attachment map, remembers host among the
+// known remotes, and notifies any connected SSE clients.
+func (s *Server) attachRemote(peer, host, busid string) (Attachment, error) {
+	if err := IsHostPort(host); err != nil {
+		return Attachment{}, fmt.Errorf("invalid host: %w", err)
+	}
+
+	port, err := s.backend.Attach(context.Background(), host, busid)
+	attach := Attachment{Port: port, Host: host, BusID: busid}
+
+	s.audit.Log(AuditEvent{
+		Peer:   peer,
+		BusID:  busid,
+		Info:   host,
+		Action: "attach",
+		Result: auditResult(err),
+		Error:  errString(err),
+	})
+	if err != nil {
+		return Attachment{}, fmt.Errorf("failed to attach device: %w", err)
+	}
+
+	s.remoteMu.Lock()
+	s.attachments[port] = attach
+	s.remoteMu.Unlock()
+
+	s.rememberRemote(host)
+	s.broadcastAttachments()
+
+	return attach, nil
+}
+
+// detachRemote releases a previously attached port.
+func (s *Server) detachRemote(peer string, port int) error {
+	s.remoteMu.RLock()
+	attach, known := s.attachments[port]
+	s.remoteMu.RUnlock()
+
+	err := s.backend.Detach(context.Background(), port)
+
+	busid := attach.BusID
+	host := attach.Host
+	s.audit.Log(AuditEvent{
+		Peer:   peer,
+		BusID:  busid,
+		Info:   host,
+		Action: "detach",
+		Result: auditResult(err),
+		Error:  errString(err),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to detach port %d: %w", port, err)
+	}
+
+	if known {
+		s.remoteMu.Lock()
+		delete(s.attachments, port)
+		s.remoteMu.Unlock()
+	}
+
+	s.broadcastAttachments()
+	return nil
+}
+
+// listAttachments returns a snapshot of currently attached remote devices.
+func (s *Server) listAttachments() []Attachment {
+	s.remoteMu.RLock()
+	defer s.remoteMu.RUnlock()
+
+	attachments := make([]Attachment, 0, len(s.attachments))
+	for _, a := range s.attachments {
+		attachments = append(attachments, a)
+	}
+	return attachments
+}
+
+// rememberRemote adds host to the configured set of known remote hosts and
+// persists the config, if it is not already known.
+func (s *Server) rememberRemote(host string) {
+	s.mu.Lock()
+	for _, existing := range s.config.Remotes {
+		if existing == host {
+			s.mu.Unlock()
+			return
+		}
+	}
+	s.config.Remotes = append(s.config.Remotes, host)
+	s.mu.Unlock()
+
+	s.saveConfig()
+}
+
+// listRemoteDevices queries every known remote host for its exportable
+// devices.
+func (s *Server) listRemoteDevices(ctx context.Context) map[string][]RemoteDevice {
+	s.mu.RLock()
+	hosts := append([]string(nil), s.config.Remotes...)
+	s.mu.RUnlock()
+
+	result := make(map[string][]RemoteDevice, len(hosts))
+	for _, host := range hosts {
+		devices, err := s.backend.ListRemote(ctx, host)
+		if err != nil {
+			s.logger.Warn("failed to list remote devices", "host", host, "error", err)
+			continue
+		}
+		result[host] = devices
+	}
+	return result
+}
+
+// broadcastAttachments pushes the current attachment snapshot to every
+// connected /events/remote SSE client.
+func (s *Server) broadcastAttachments() {
+	s.remoteClientMu.Lock()
+	defer s.remoteClientMu.Unlock()
+
+	attachments := s.listAttachments()
+	for client := range s.remoteClients {
+		select {
+		case client <- attachments:
+		default:
+			// Client is slow, skip
+		}
+	}
+}
+
+func (s *Server) addRemoteClient(client chan []Attachment) {
+	s.remoteClientMu.Lock()
+	s.remoteClients[client] = struct{}{}
+	s.remoteClientMu.Unlock()
+}
+
+func (s *Server) removeRemoteClient(client chan []Attachment) {
+	s.remoteClientMu.Lock()
+	delete(s.remoteClients, client)
+	close(client)
+	s.remoteClientMu.Unlock()
+}
@@ -0,0 +1,111 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+)
+
+// handleRemotes lists the devices exported by every known remote host.
+func (s *Server) handleRemotes(w http.ResponseWriter, r *http.Request) {
+	devicesByHost := s.listRemoteDevices(r.Context())
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"remotes":     devicesByHost,
+		"attachments": s.listAttachments(),
+	})
+}
+
+// handleAttach imports a device from a remote host.
+func (s *Server) handleAttach(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Host  string `json:"host"`
+		BusID string `json:"busid"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	attach, err := s.attachRemote(peerDeviceID(r), req.Host, req.BusID)
+	if err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":     "success",
+		"attachment": attach,
+	})
+}
+
+// handleDetach releases a previously attached remote device.
+func (s *Server) handleDetach(w http.ResponseWriter, r *http.Request) {
+	if r.Method != http.MethodPost {
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+		return
+	}
+
+	var req struct {
+		Port int `json:"port"`
+	}
+
+	if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+		http.Error(w, "Bad Request", http.StatusBadRequest)
+		return
+	}
+
+	if err := s.detachRemote(peerDeviceID(r), req.Port); err != nil {
+		w.WriteHeader(http.StatusInternalServerError)
+		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+		return
+	}
+
+	w.Header().Set("Content-Type", "application/json")
+	json.NewEncoder(w).Encode(map[string]interface{}{
+		"status":      "success",
+		"attachments": s.listAttachments(),
+	})
+}
+
+// handleRemoteEvents streams attachment-state changes over SSE, mirroring
+// handleEvents for local device state.
+func (s *Server) handleRemoteEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	client := make(chan []Attachment, 10)
+	s.addRemoteClient(client)
+	defer s.removeRemoteClient(client)
+
+	data, _ := json.Marshal(s.listAttachments())
+	fmt.Fprintf(w, "data: %s\n\n", data)
+	flusher.Flush()
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case attachments := <-client:
+			data, _ := json.Marshal(attachments)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"fmt"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// Rule declaratively matches USB devices by stable identity (VID/PID,
+// serial, ...) rather than by busid, which changes across reboots and
+// replugs. A Rule that matches a device with AutoBind set exports it
+// automatically; one with AutoAttachTo set attaches it from the named
+// remote host once it is seen there.
+type Rule struct {
+	Name         string
+	Match        map[string]string
+	AutoBind     bool
+	AutoAttachTo string
+}
+
+// Validator checks a single predicate value, returning a descriptive error
+// if it is malformed.
+type Validator func(value string) error
+
+var hexIDPattern = regexp.MustCompile(`^[0-9a-fA-F]{4}$`)
+var interfaceClassPattern = regexp.MustCompile(`^[0-9a-fA-F]{2}$`)
+var busPathPattern = regexp.MustCompile(`^[0-9]+(-[0-9]+(\.[0-9]+)*)?$`)
+
+// IsHexID validates a 4-digit hex VID/PID value, e.g. "046d".
+func IsHexID(value string) error {
+	if !hexIDPattern.MatchString(value) {
+		return fmt.Errorf("must be a 4-digit hex ID, got %q", value)
+	}
+	return nil
+}
+
+// IsInterfaceClass validates a 2-digit hex bInterfaceClass value, e.g.
+// "09", matching the width sysfs reports it in.
+func IsInterfaceClass(value string) error {
+	if !interfaceClassPattern.MatchString(value) {
+		return fmt.Errorf("must be a 2-digit hex interface class, got %q", value)
+	}
+	return nil
+}
+
+// hasConfigDelimiter reports whether value contains a byte that would let it
+// escape its field in the flat key=value config format: \n or \r would
+// split it into a second, independently-parsed config line, and ';' or '='
+// would inject an extra field into a "rule=" line.
+func hasConfigDelimiter(value string) bool {
+	return strings.ContainsAny(value, "\n\r;=")
+}
+
+// IsNonEmpty validates that value is not blank and cannot break out of its
+// field when persisted into the config file.
+func IsNonEmpty(value string) error {
+	if strings.TrimSpace(value) == "" {
+		return fmt.Errorf("must not be empty")
+	}
+	if hasConfigDelimiter(value) {
+		return fmt.Errorf("must not contain newlines, ';', or '=', got %q", value)
+	}
+	return nil
+}
+
+// IsBusPath validates a bus number ("1") or bus-port path ("1-1.2").
+func IsBusPath(value string) error {
+	if !busPathPattern.MatchString(value) {
+		return fmt.Errorf("must be a bus number or bus-port path, got %q", value)
+	}
+	return nil
+}
+
+// IsHostPort validates "host" or "host:port".
+func IsHostPort(value string) error {
+	if hasConfigDelimiter(value) {
+		return fmt.Errorf("must not contain newlines, ';', or '=', got %q", value)
+	}
+
+	host, port, err := net.SplitHostPort(value)
+	if err != nil {
+		// A bare host with no port is also acceptable.
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("must not be empty")
+		}
+		return nil
+	}
+	if host == "" {
+		return fmt.Errorf("must include a host")
+	}
+	if p, err := strconv.Atoi(port); err != nil || p < 1 || p > 65535 {
+		return fmt.Errorf("port must be between 1 and 65535, got %q", port)
+	}
+	return nil
+}
+
+// matchValidators maps each supported Match predicate to the validator
+// that checks its value.
+var matchValidators = map[string]Validator{
+	"vendor_id":       IsHexID,
+	"product_id":      IsHexID,
+	"serial":          IsNonEmpty,
+	"interface_class": IsInterfaceClass,
+	"bus":             IsBusPath,
+	"port_path":       IsBusPath,
+}
+
+// ValidateRule runs every applicable validator against r and returns all
+// failures found, rather than stopping at the first.
+func ValidateRule(r Rule) []error {
+	var errs []error
+
+	if err := IsNonEmpty(r.Name); err != nil {
+		errs = append(errs, fmt.Errorf("name: %w", err))
+	}
+
+	for key, value := range r.Match {
+		validate, known := matchValidators[key]
+		if !known {
+			errs = append(errs, fmt.Errorf("unknown match predicate %q", key))
+			continue
+		}
+		if err := validate(value); err != nil {
+			errs = append(errs, fmt.Errorf("match.%s: %w", key, err))
+		}
+	}
+
+	if r.AutoAttachTo != "" {
+		if err := IsHostPort(r.AutoAttachTo); err != nil {
+			errs = append(errs, fmt.Errorf("auto_attach_to: %w", err))
+		}
+	}
+
+	return errs
+}
+
+// ParseRuleLine parses the compact form rules are stored in, one rule per
+// config line: "name=webcam;vendor_id=046d;product_id=c52b;auto_bind=true".
+// Any key other than name/auto_bind/auto_attach_to becomes a Match
+// predicate.
+func ParseRuleLine(raw string) (Rule, error) {
+	rule := Rule{Match: map[string]string{}}
+
+	for _, field := range strings.Split(raw, ";") {
+		field = strings.TrimSpace(field)
+		if field == "" {
+			continue
+		}
+
+		parts := strings.SplitN(field, "=", 2)
+		if len(parts) != 2 {
+			return Rule{}, fmt.Errorf("malformed field %q (want key=value)", field)
+		}
+		key, value := strings.TrimSpace(parts[0]), strings.TrimSpace(parts[1])
+
+		switch key {
+		case "name":
+			rule.Name = value
+		case "auto_bind":
+			rule.AutoBind = value == "true"
+		case "auto_attach_to":
+			rule.AutoAttachTo = value
+		default:
+			rule.Match[key] = value
+		}
+	}
+
+	return rule, nil
+}
+
+// FormatRuleLine renders r back into the config-line form ParseRuleLine
+// accepts, with Match keys sorted for a stable, diffable config file.
+func FormatRuleLine(r Rule) string {
+	fields := []string{"name=" + r.Name}
+
+	keys := make([]string, 0, len(r.Match))
+	for k := range r.Match {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fields = append(fields, k+"="+r.Match[k])
+	}
+
+	if r.AutoBind {
+		fields = append(fields, "auto_bind=true")
+	}
+	if r.AutoAttachTo != "" {
+		fields = append(fields, "auto_attach_to="+r.AutoAttachTo)
+	}
+
+	return strings.Join(fields, ";")
+}
+
+// matchesDescriptors reports whether every predicate in r.Match agrees
+// with the corresponding field in d. A rule with no predicates, or with a
+// predicate key it does not know, matches nothing.
+func (r Rule) matchesDescriptors(d usbDescriptors) bool {
+	for key, want := range r.Match {
+		var got string
+		switch key {
+		case "vendor_id":
+			got = d.VendorID
+		case "product_id":
+			got = d.ProductID
+		case "serial":
+			got = d.Serial
+		case "interface_class":
+			got = d.InterfaceClass
+		case "bus":
+			got = d.Bus
+		case "port_path":
+			got = d.PortPath
+		default:
+			return false
+		}
+		if !strings.EqualFold(got, want) {
+			return false
+		}
+	}
+	return len(r.Match) > 0
+}
+
+// RuleEvent records why a rule fired, for the /events/rules SSE stream.
+type RuleEvent struct {
+	Rule   string `json:"rule"`
+	BusID  string `json:"busid,omitempty"`
+	Host   string `json:"host,omitempty"`
+	Action string `json:"action"`
+}
@@ -0,0 +1,185 @@
+package main
+
+import (
+	"context"
+	"regexp"
+	"strings"
+)
+
+// remoteIDPattern pulls a "(vvvv:pppp)" VID:PID pair out of the free-form
+// info string usbip list -r prints for a remote device, since RemoteDevice
+// carries no structured descriptor fields of its own.
+var remoteIDPattern = regexp.MustCompile(`\(([0-9a-fA-F]{4}):([0-9a-fA-F]{4})\)`)
+
+// evaluateRules checks every configured rule against the freshly-polled
+// local devices (AutoBind) and every known remote host's exported devices
+// (AutoAttachTo), firing the matching action and an SSE notification.
+func (s *Server) evaluateRules(devices []Device) {
+	s.mu.RLock()
+	rules := append([]Rule(nil), s.config.Rules...)
+	s.mu.RUnlock()
+
+	s.applyAutoBind(rules, devices)
+	s.applyAutoUnbind(rules, devices)
+
+	for _, rule := range rules {
+		if rule.AutoAttachTo != "" {
+			s.applyAutoAttach(rule)
+		}
+	}
+}
+
+// deviceDescriptors converts a polled Device's identity fields into the
+// usbDescriptors shape Rule.matchesDescriptors expects.
+func deviceDescriptors(d Device) usbDescriptors {
+	return usbDescriptors{
+		VendorID:       d.VendorID,
+		ProductID:      d.ProductID,
+		Serial:         d.Serial,
+		InterfaceClass: d.InterfaceClass,
+		Bus:            strings.SplitN(d.BusID, "-", 2)[0],
+		PortPath:       d.BusID,
+	}
+}
+
+func (s *Server) applyAutoBind(rules []Rule, devices []Device) {
+	boundThisPass := make(map[string]bool)
+
+	for _, rule := range rules {
+		if !rule.AutoBind {
+			continue
+		}
+
+		for _, d := range devices {
+			if d.Bound || boundThisPass[d.BusID] {
+				continue
+			}
+			if !rule.matchesDescriptors(deviceDescriptors(d)) {
+				continue
+			}
+
+			s.logger.Info("rule matched, auto-binding", "rule", rule.Name, "busid", d.BusID)
+			if err := s.bindDevice("rule:"+rule.Name, d.BusID); err != nil {
+				s.logger.Error("rule failed to auto-bind", "rule", rule.Name, "busid", d.BusID, "error", err)
+				continue
+			}
+
+			boundThisPass[d.BusID] = true
+			s.ruleBoundMu.Lock()
+			s.ruleBound[d.BusID] = rule.Name
+			s.ruleBoundMu.Unlock()
+			s.fireRuleEvent(RuleEvent{Rule: rule.Name, BusID: d.BusID, Action: "auto_bind"})
+		}
+	}
+}
+
+// applyAutoUnbind releases devices the engine previously auto-bound but
+// that no longer match any AutoBind rule, because the matching rule was
+// deleted, its descriptors changed, or auto_bind was flipped off. Devices
+// the operator bound by hand (never recorded in s.ruleBound) are left
+// alone.
+func (s *Server) applyAutoUnbind(rules []Rule, devices []Device) {
+	for _, d := range devices {
+		if !d.Bound {
+			continue
+		}
+
+		s.ruleBoundMu.Lock()
+		ruleName, tracked := s.ruleBound[d.BusID]
+		s.ruleBoundMu.Unlock()
+		if !tracked {
+			continue
+		}
+
+		if matchesAnyAutoBindRule(rules, d) {
+			continue
+		}
+
+		s.logger.Info("rule no longer matches, auto-unbinding", "rule", ruleName, "busid", d.BusID)
+		if err := s.unbindDevice("rule:"+ruleName, d.BusID); err != nil {
+			s.logger.Error("rule failed to auto-unbind", "rule", ruleName, "busid", d.BusID, "error", err)
+			continue
+		}
+
+		s.ruleBoundMu.Lock()
+		delete(s.ruleBound, d.BusID)
+		s.ruleBoundMu.Unlock()
+		s.fireRuleEvent(RuleEvent{Rule: ruleName, BusID: d.BusID, Action: "auto_unbind"})
+	}
+}
+
+func matchesAnyAutoBindRule(rules []Rule, d Device) bool {
+	descriptors := deviceDescriptors(d)
+	for _, rule := range rules {
+		if rule.AutoBind && rule.matchesDescriptors(descriptors) {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *Server) applyAutoAttach(rule Rule) {
+	remotes, err := s.backend.ListRemote(context.Background(), rule.AutoAttachTo)
+	if err != nil {
+		s.logger.Warn("rule failed to list remote devices", "rule", rule.Name, "host", rule.AutoAttachTo, "error", err)
+		return
+	}
+
+	attached := make(map[string]bool)
+	for _, a := range s.listAttachments() {
+		attached[a.Host+"|"+a.BusID] = true
+	}
+
+	for _, d := range remotes {
+		if attached[rule.AutoAttachTo+"|"+d.BusID] {
+			continue
+		}
+
+		match := remoteIDPattern.FindStringSubmatch(d.Info)
+		if match == nil {
+			continue
+		}
+
+		descriptors := usbDescriptors{VendorID: match[1], ProductID: match[2], PortPath: d.BusID}
+		if !rule.matchesDescriptors(descriptors) {
+			continue
+		}
+
+		s.logger.Info("rule matched remote device, auto-attaching", "rule", rule.Name, "busid", d.BusID, "host", rule.AutoAttachTo)
+		if _, err := s.attachRemote("rule:"+rule.Name, rule.AutoAttachTo, d.BusID); err != nil {
+			s.logger.Error("rule failed to auto-attach", "rule", rule.Name, "busid", d.BusID, "error", err)
+			continue
+		}
+
+		s.fireRuleEvent(RuleEvent{Rule: rule.Name, BusID: d.BusID, Host: rule.AutoAttachTo, Action: "auto_attach"})
+	}
+}
+
+// fireRuleEvent notifies every connected /events/rules SSE client that a
+// rule just took an action, so operators can see why a device was bound
+// or attached without digging through logs.
+func (s *Server) fireRuleEvent(event RuleEvent) {
+	s.ruleClientMu.Lock()
+	defer s.ruleClientMu.Unlock()
+
+	for client := range s.ruleClients {
+		select {
+		case client <- event:
+		default:
+			// Client is slow, skip
+		}
+	}
+}
+
+func (s *Server) addRuleClient(client chan RuleEvent) {
+	s.ruleClientMu.Lock()
+	s.ruleClients[client] = struct{}{}
+	s.ruleClientMu.Unlock()
+}
+
+func (s *Server) removeRuleClient(client chan RuleEvent) {
+	s.ruleClientMu.Lock()
+	delete(s.ruleClients, client)
+	close(client)
+	s.ruleClientMu.Unlock()
+}
@@ -0,0 +1,103 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+)
+
+// handleRules serves GET to list the configured rules and PUT to replace
+// them wholesale, validating every rule before any of them are persisted.
+func (s *Server) handleRules(w http.ResponseWriter, r *http.Request) {
+	switch r.Method {
+	case http.MethodGet:
+		s.mu.RLock()
+		rules := append([]Rule(nil), s.config.Rules...)
+		s.mu.RUnlock()
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(rules)
+
+	case http.MethodPut:
+		var rules []Rule
+		if err := json.NewDecoder(r.Body).Decode(&rules); err != nil {
+			http.Error(w, "Bad Request", http.StatusBadRequest)
+			return
+		}
+
+		if err := validateRules(rules); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		s.mu.Lock()
+		s.config.Rules = rules
+		s.mu.Unlock()
+
+		err := s.saveConfig()
+		s.audit.Log(AuditEvent{
+			Peer:   peerDeviceID(r),
+			Info:   fmt.Sprintf("%d rule(s)", len(rules)),
+			Action: "rules_update",
+			Result: auditResult(err),
+			Error:  errString(err),
+		})
+		if err != nil {
+			w.WriteHeader(http.StatusInternalServerError)
+			json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
+			return
+		}
+
+		w.Header().Set("Content-Type", "application/json")
+		json.NewEncoder(w).Encode(map[string]interface{}{"status": "success", "rules": rules})
+
+	default:
+		http.Error(w, "Method Not Allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+// validateRules aggregates ValidateRule's findings across a whole rule
+// set into one error, so a PUT either fully succeeds or fully fails.
+func validateRules(rules []Rule) error {
+	var errs []error
+	for _, rule := range rules {
+		for _, err := range ValidateRule(rule) {
+			errs = append(errs, fmt.Errorf("rule %q: %w", rule.Name, err))
+		}
+	}
+	if len(errs) == 0 {
+		return nil
+	}
+	return fmt.Errorf("%d invalid rule(s): %w", len(errs), errors.Join(errs...))
+}
+
+// handleRuleEvents streams RuleEvents over SSE as rules fire, so operators
+// can see why a device was just bound or attached.
+func (s *Server) handleRuleEvents(w http.ResponseWriter, r *http.Request) {
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.Header().Set("Connection", "keep-alive")
+
+	flusher, ok := w.(http.Flusher)
+	if !ok {
+		http.Error(w, "Streaming unsupported", http.StatusInternalServerError)
+		return
+	}
+
+	client := make(chan RuleEvent, 10)
+	s.addRuleClient(client)
+	defer s.removeRuleClient(client)
+
+	for {
+		select {
+		case <-r.Context().Done():
+			return
+		case event := <-client:
+			data, _ := json.Marshal(event)
+			fmt.Fprintf(w, "data: %s\n\n", data)
+			flusher.Flush()
+		}
+	}
+}
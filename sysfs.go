@@ -0,0 +1,59 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const sysfsUSBDevicesPath = "/sys/bus/usb/devices"
+
+// usbDescriptors holds the VID/PID/serial/class fields rules match
+// against, read straight from sysfs rather than parsed out of lsusb -v
+// text, since sysfs is keyed by the same busid usbip already uses.
+type usbDescriptors struct {
+	VendorID       string
+	ProductID      string
+	Serial         string
+	InterfaceClass string
+	Bus            string
+	PortPath       string
+}
+
+// readUSBDescriptors reads the sysfs attributes for busid. Missing files
+// (no permission, no such attribute, non-Linux host) simply leave the
+// corresponding field empty rather than erroring, since descriptors are
+// best-effort enrichment for rule matching, not required for bind/unbind.
+func readUSBDescriptors(busid string) usbDescriptors {
+	devDir := filepath.Join(sysfsUSBDevicesPath, busid)
+
+	d := usbDescriptors{
+		Bus:      strings.SplitN(busid, "-", 2)[0],
+		PortPath: busid,
+	}
+	d.VendorID = readSysfsAttr(filepath.Join(devDir, "idVendor"))
+	d.ProductID = readSysfsAttr(filepath.Join(devDir, "idProduct"))
+	d.Serial = readSysfsAttr(filepath.Join(devDir, "serial"))
+	d.InterfaceClass = readFirstInterfaceClass(devDir, busid)
+
+	return d
+}
+
+func readSysfsAttr(path string) string {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(data))
+}
+
+// readFirstInterfaceClass finds the bInterfaceClass of the device's first
+// interface, which lives in a subdirectory of devDir named
+// "<busid>:<config>.<interface>".
+func readFirstInterfaceClass(devDir, busid string) string {
+	matches, err := filepath.Glob(filepath.Join(devDir, busid+":*"))
+	if err != nil || len(matches) == 0 {
+		return ""
+	}
+	return readSysfsAttr(filepath.Join(matches[0], "bInterfaceClass"))
+}
@@ -0,0 +1,49 @@
+package main
+
+import (
+	"net/http"
+)
+
+// requireTrustedPeer wraps an HTTP handler so it only runs for clients
+// whose mTLS certificate hashes to a DeviceID present in the trusted-peer
+// allow-list. Server.Run only requests, rather than requires, a client
+// certificate at the TLS layer so the web UI stays reachable without one;
+// this is the actual enforcement point, rejecting requests with no
+// certificate or an untrusted one.
+func (s *Server) requireTrustedPeer(next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+			http.Error(w, "client certificate required", http.StatusUnauthorized)
+			return
+		}
+
+		peerID := DeviceIDFromCert(r.TLS.PeerCertificates[0].Raw)
+		if !s.isTrustedPeer(peerID) {
+			s.logger.Warn("rejected request from untrusted peer", "peer", peerID)
+			http.Error(w, "untrusted peer", http.StatusForbidden)
+			return
+		}
+
+		next(w, r)
+	}
+}
+
+// isTrustedPeer reports whether id appears in the configured trusted-peer
+// allow-list.
+func (s *Server) isTrustedPeer(id string) bool {
+	for _, trusted := range s.config.TrustedPeers {
+		if trusted == id {
+			return true
+		}
+	}
+	return false
+}
+
+// peerDeviceID returns the DeviceID of the mTLS client certificate that
+// authenticated r, or "" if the request has none.
+func peerDeviceID(r *http.Request) string {
+	if r.TLS == nil || len(r.TLS.PeerCertificates) == 0 {
+		return ""
+	}
+	return DeviceIDFromCert(r.TLS.PeerCertificates[0].Raw)
+}
@@ -3,13 +3,16 @@ package main
 import (
 	"bufio"
 	"context"
+	"crypto/tls"
 	"embed"
 	"encoding/json"
+	"errors"
 	"flag"
 	"fmt"
 	"html/template"
 	"io"
 	"log"
+	"log/slog"
 	"net"
 	"net/http"
 	"os"
@@ -48,37 +51,90 @@ type Config struct {
 	ConfigPath    string
 	VerboseLog    bool
 	BoundDevices  []string
+	Backend       string
+	TrustedPeers  []string
+	LogFile       string
+	LogMaxSizeMB  int
+	LogMaxBackups int
+	LogMaxAgeDays int
+	LogCompress   bool
+	Remotes       []string
+	Rules         []Rule
 }
 
 // Device represents a USB device
 type Device struct {
-	BusID string `json:"busid"`
-	Info  string `json:"info"`
-	Bound bool   `json:"bound"`
+	BusID          string `json:"busid"`
+	Info           string `json:"info"`
+	Bound          bool   `json:"bound"`
+	VendorID       string `json:"vendor_id,omitempty"`
+	ProductID      string `json:"product_id,omitempty"`
+	Serial         string `json:"serial,omitempty"`
+	InterfaceClass string `json:"interface_class,omitempty"`
 }
 
 // Server manages the HTTP server and device state
 type Server struct {
 	config   *Config
+	backend  Backend
+	identity *Identity
+	audit    *AuditLogger
 	devices  []Device
 	mu       sync.RWMutex
 	clients  map[chan []Device]struct{}
 	clientMu sync.Mutex
-	logger   *log.Logger
+	logger   *slog.Logger
+
+	attachments    map[int]Attachment
+	remoteMu       sync.RWMutex
+	remoteClients  map[chan []Attachment]struct{}
+	remoteClientMu sync.Mutex
+
+	ruleClients  map[chan RuleEvent]struct{}
+	ruleClientMu sync.Mutex
+
+	// ruleBound tracks busid -> name of the rule that auto-bound it, so
+	// applyAutoUnbind can release only devices the engine itself claimed
+	// and leave devices the operator bound by hand alone.
+	ruleBound   map[string]string
+	ruleBoundMu sync.Mutex
 }
 
 // NewServer creates a new server instance
 func NewServer(cfg *Config) *Server {
-	logger := log.New(os.Stdout, "", log.LstdFlags)
+	logWriter := io.Writer(os.Stdout)
 	if !cfg.VerboseLog {
-		logger.SetOutput(io.Discard)
+		logWriter = io.Discard
+	}
+	logger := slog.New(slog.NewTextHandler(logWriter, nil))
+
+	backendName := cfg.Backend
+	if backendName == "" {
+		backendName = defaultBackendName()
+	}
+	backend, err := NewBackend(backendName)
+	if err != nil {
+		logger.Warn("unknown backend, falling back", "backend", backendName, "fallback", defaultBackendName(), "error", err)
+		backend, _ = NewBackend(defaultBackendName())
+	}
+
+	audit, err := NewAuditLogger(cfg)
+	if err != nil {
+		logger.Warn("failed to open audit log, discarding audit events", "path", cfg.LogFile, "error", err)
+		audit, _ = NewAuditLogger(&Config{})
 	}
 
 	return &Server{
-		config:  cfg,
-		devices: []Device{},
-		clients: make(map[chan []Device]struct{}),
-		logger:  logger,
+		config:        cfg,
+		backend:       backend,
+		audit:         audit,
+		devices:       []Device{},
+		clients:       make(map[chan []Device]struct{}),
+		logger:        logger,
+		attachments:   make(map[int]Attachment),
+		remoteClients: make(map[chan []Attachment]struct{}),
+		ruleClients:   make(map[chan RuleEvent]struct{}),
+		ruleBound:     make(map[string]string),
 	}
 }
 
@@ -103,8 +159,12 @@ func loadConfig(path string) (*Config, error) {
 	}
 	defer file.Close()
 
+	var ruleErrs []error
+
 	scanner := bufio.NewScanner(file)
+	lineNum := 0
 	for scanner.Scan() {
+		lineNum++
 		line := strings.TrimSpace(scanner.Text())
 		if line == "" || strings.HasPrefix(line, "#") {
 			continue
@@ -127,10 +187,46 @@ func loadConfig(path string) (*Config, error) {
 			cfg.PollInterval = time.Duration(interval) * time.Second
 		case "bound_device":
 			cfg.BoundDevices = append(cfg.BoundDevices, value)
+		case "backend":
+			cfg.Backend = value
+		case "trusted_peer":
+			cfg.TrustedPeers = append(cfg.TrustedPeers, value)
+		case "log_file":
+			cfg.LogFile = value
+		case "log_max_size":
+			fmt.Sscanf(value, "%d", &cfg.LogMaxSizeMB)
+		case "log_max_backups":
+			fmt.Sscanf(value, "%d", &cfg.LogMaxBackups)
+		case "log_max_age":
+			fmt.Sscanf(value, "%d", &cfg.LogMaxAgeDays)
+		case "log_compress":
+			cfg.LogCompress = value == "true"
+		case "remote":
+			cfg.Remotes = append(cfg.Remotes, value)
+		case "rule":
+			rule, err := ParseRuleLine(value)
+			if err != nil {
+				ruleErrs = append(ruleErrs, fmt.Errorf("line %d: %w", lineNum, err))
+				continue
+			}
+			if errs := ValidateRule(rule); len(errs) > 0 {
+				for _, e := range errs {
+					ruleErrs = append(ruleErrs, fmt.Errorf("line %d: rule %q: %w", lineNum, rule.Name, e))
+				}
+				continue
+			}
+			cfg.Rules = append(cfg.Rules, rule)
 		}
 	}
 
-	return cfg, scanner.Err()
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	if len(ruleErrs) > 0 {
+		return cfg, fmt.Errorf("invalid rules in %s: %w", path, errors.Join(ruleErrs...))
+	}
+
+	return cfg, nil
 }
 
 // saveConfig saves configuration to file
@@ -153,6 +249,25 @@ func (s *Server) saveConfig() error {
 	fmt.Fprintf(file, "port=%d\n", s.config.Port)
 	fmt.Fprintf(file, "bind=%s\n", s.config.BindAddress)
 	fmt.Fprintf(file, "poll_interval=%d\n", int(s.config.PollInterval.Seconds()))
+	if s.config.Backend != "" {
+		fmt.Fprintf(file, "backend=%s\n", s.config.Backend)
+	}
+	for _, peer := range s.config.TrustedPeers {
+		fmt.Fprintf(file, "trusted_peer=%s\n", peer)
+	}
+	if s.config.LogFile != "" {
+		fmt.Fprintf(file, "log_file=%s\n", s.config.LogFile)
+		fmt.Fprintf(file, "log_max_size=%d\n", s.config.LogMaxSizeMB)
+		fmt.Fprintf(file, "log_max_backups=%d\n", s.config.LogMaxBackups)
+		fmt.Fprintf(file, "log_max_age=%d\n", s.config.LogMaxAgeDays)
+		fmt.Fprintf(file, "log_compress=%t\n", s.config.LogCompress)
+	}
+	for _, remote := range s.config.Remotes {
+		fmt.Fprintf(file, "remote=%s\n", remote)
+	}
+	for _, rule := range s.config.Rules {
+		fmt.Fprintf(file, "rule=%s\n", FormatRuleLine(rule))
+	}
 
 	s.mu.RLock()
 	for _, dev := range s.devices {
@@ -172,20 +287,22 @@ func validateBusID(busid string) bool {
 	return match
 }
 
-// execCommand safely executes a command with validation
-func execCommand(name string, args ...string) (string, error) {
+// execCommandContext safely executes a command with validation
+func execCommandContext(ctx context.Context, name string, args ...string) (string, error) {
 	// Whitelist of allowed commands
 	allowed := map[string]bool{
-		"usbip":   true,
-		"lsusb":   true,
-		"usbipd":  true,
+		"usbip":          true,
+		"lsusb":          true,
+		"usbipd":         true,
+		"vhclientx86_64": true,
+		"vhusbdx86_64":   true,
 	}
 
 	if !allowed[name] {
 		return "", fmt.Errorf("command not allowed: %s", name)
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Second)
 	defer cancel()
 
 	cmd := exec.CommandContext(ctx, name, args...)
@@ -197,97 +314,43 @@ func execCommand(name string, args ...string) (string, error) {
 	return string(output), nil
 }
 
-// isDeviceBound checks if a device is bound to usbip-host
-func isDeviceBound(busid string) bool {
-	if runtime.GOOS == "windows" {
-		// On Windows, check via usbipd command
-		return false // Simplified for now
-	}
-
-	path := filepath.Join("/sys/bus/usb/drivers/usbip-host", busid)
-	_, err := os.Stat(path)
-	return err == nil
+// execCommand is execCommandContext against a background context.
+func execCommand(name string, args ...string) (string, error) {
+	return execCommandContext(context.Background(), name, args...)
 }
 
-// listDevices retrieves list of USB devices
+// listDevices retrieves list of USB devices via the configured backend
 func (s *Server) listDevices() ([]Device, error) {
-	var cmd string
-	var args []string
-
-	if runtime.GOOS == "windows" {
-		cmd = "usbipd"
-		args = []string{"wsl", "list"}
-	} else {
-		cmd = "usbip"
-		args = []string{"list", "-l"}
-	}
-
-	output, err := execCommand(cmd, args...)
-	if err != nil {
-		return nil, err
-	}
-
-	return s.parseDevices(output), nil
+	return s.backend.List(context.Background())
 }
 
-// parseDevices parses usbip list output
-func (s *Server) parseDevices(output string) []Device {
-	var devices []Device
-	var current *Device
-
-	scanner := bufio.NewScanner(strings.NewReader(output))
-	for scanner.Scan() {
-		line := scanner.Text()
-		trimmed := strings.TrimSpace(line)
-
-		if trimmed == "" {
-			continue
-		}
-
-		// New device line
-		if strings.HasPrefix(trimmed, "- busid") || strings.HasPrefix(trimmed, "BUSID") {
-			if current != nil {
-				devices = append(devices, *current)
-			}
-
-			current = &Device{}
-			
-			// Extract bus ID
-			parts := strings.Fields(trimmed)
-			for i, part := range parts {
-				if (part == "busid" || part == "BUSID") && i+1 < len(parts) {
-					busid := parts[i+1]
-					// Remove trailing characters
-					busid = strings.TrimRight(busid, ":()")
-					current.BusID = busid
-					current.Bound = isDeviceBound(busid)
-					break
-				}
-			}
-		} else if current != nil && (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) {
-			// Device info line (indented)
-			if current.Info != "" {
-				current.Info += " "
-			}
-			current.Info += trimmed
-		}
-	}
-
-	if current != nil {
-		devices = append(devices, *current)
+// updateDevices polls for device changes and evaluates rules against the
+// result. It must only be called from the poll loop: bindDevice/unbindDevice
+// call refreshDevices instead, since routing their post-action refresh
+// through evaluateRules would re-enter applyAutoBind/applyAutoAttach from
+// inside a rule action and re-run them for the same tick.
+func (s *Server) updateDevices() {
+	devices, ok := s.refreshDevices()
+	if !ok {
+		return
 	}
 
-	return devices
+	s.evaluateRules(devices)
 }
 
-// updateDevices polls for device changes
-func (s *Server) updateDevices() {
+// refreshDevices re-lists devices from the backend, enriches them, updates
+// the cached s.devices, and broadcasts to /events clients if any device's
+// busid or bound state changed (see devicesEqual). It returns the fresh
+// list and whether the list succeeded.
+func (s *Server) refreshDevices() ([]Device, bool) {
 	devices, err := s.listDevices()
 	if err != nil {
-		s.logger.Printf("Failed to list devices: %v", err)
-		return
+		s.logger.Error("failed to list devices", "error", err)
+		return nil, false
 	}
 
+	enrichDevices(devices)
+
 	s.mu.Lock()
 	changed := !devicesEqual(s.devices, devices)
 	s.devices = devices
@@ -296,6 +359,21 @@ func (s *Server) updateDevices() {
 	if changed {
 		s.broadcastUpdate()
 	}
+
+	return devices, true
+}
+
+// enrichDevices fills in each device's VID/PID/serial/interface-class
+// fields from sysfs, so rules can match on stable identity rather than
+// busid.
+func enrichDevices(devices []Device) {
+	for i := range devices {
+		d := readUSBDescriptors(devices[i].BusID)
+		devices[i].VendorID = d.VendorID
+		devices[i].ProductID = d.ProductID
+		devices[i].Serial = d.Serial
+		devices[i].InterfaceClass = d.InterfaceClass
+	}
 }
 
 // devicesEqual compares two device slices
@@ -314,61 +392,74 @@ func devicesEqual(a, b []Device) bool {
 }
 
 // bindDevice binds a USB device
-func (s *Server) bindDevice(busid string) error {
-	if !validateBusID(busid) {
-		return fmt.Errorf("invalid bus ID: %s", busid)
-	}
-
-	var cmd string
-	var args []string
-
-	if runtime.GOOS == "windows" {
-		cmd = "usbipd"
-		args = []string{"wsl", "attach", "--busid", busid}
-	} else {
-		cmd = "usbip"
-		args = []string{"bind", "-b", busid}
-	}
-
-	_, err := execCommand(cmd, args...)
+func (s *Server) bindDevice(peer, busid string) error {
+	err := s.backend.Bind(context.Background(), busid)
+	s.audit.Log(AuditEvent{
+		Peer:   peer,
+		BusID:  busid,
+		Info:   s.deviceInfo(busid),
+		Action: "bind",
+		Result: auditResult(err),
+		Error:  errString(err),
+	})
 	if err != nil {
 		return fmt.Errorf("failed to bind device: %w", err)
 	}
 
-	s.logger.Printf("Bound device: %s", busid)
-	s.updateDevices()
+	s.logger.Info("bound device", "busid", busid)
+	s.refreshDevices()
 	s.saveConfig()
 
 	return nil
 }
 
 // unbindDevice unbinds a USB device
-func (s *Server) unbindDevice(busid string) error {
-	if !validateBusID(busid) {
-		return fmt.Errorf("invalid bus ID: %s", busid)
+func (s *Server) unbindDevice(peer, busid string) error {
+	err := s.backend.Unbind(context.Background(), busid)
+	s.audit.Log(AuditEvent{
+		Peer:   peer,
+		BusID:  busid,
+		Info:   s.deviceInfo(busid),
+		Action: "unbind",
+		Result: auditResult(err),
+		Error:  errString(err),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to unbind device: %w", err)
 	}
 
-	var cmd string
-	var args []string
+	s.logger.Info("unbound device", "busid", busid)
+	s.refreshDevices()
+	s.saveConfig()
 
-	if runtime.GOOS == "windows" {
-		cmd = "usbipd"
-		args = []string{"wsl", "detach", "--busid", busid}
-	} else {
-		cmd = "usbip"
-		args = []string{"unbind", "-b", busid}
+	return nil
+}
+
+// deviceInfo returns the last-known description string for busid, for
+// inclusion in audit events.
+func (s *Server) deviceInfo(busid string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	for _, d := range s.devices {
+		if d.BusID == busid {
+			return d.Info
+		}
 	}
+	return ""
+}
 
-	_, err := execCommand(cmd, args...)
+func auditResult(err error) string {
 	if err != nil {
-		return fmt.Errorf("failed to unbind device: %w", err)
+		return "error"
 	}
+	return "success"
+}
 
-	s.logger.Printf("Unbound device: %s", busid)
-	s.updateDevices()
-	s.saveConfig()
-
-	return nil
+func errString(err error) string {
+	if err != nil {
+		return err.Error()
+	}
+	return ""
 }
 
 // SSE handlers
@@ -451,7 +542,7 @@ func (s *Server) handleBind(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.bindDevice(req.BusID); err != nil {
+	if err := s.bindDevice(peerDeviceID(r), req.BusID); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
@@ -484,7 +575,7 @@ func (s *Server) handleUnbind(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	if err := s.unbindDevice(req.BusID); err != nil {
+	if err := s.unbindDevice(peerDeviceID(r), req.BusID); err != nil {
 		w.WriteHeader(http.StatusInternalServerError)
 		json.NewEncoder(w).Encode(map[string]string{"error": err.Error()})
 		return
@@ -567,12 +658,23 @@ func (s *Server) startPolling(ctx context.Context) {
 
 // Run starts the server
 func (s *Server) Run(ctx context.Context) error {
+	if s.identity == nil {
+		return fmt.Errorf("server identity not set")
+	}
+	defer s.audit.Close()
+
 	mux := http.NewServeMux()
 	mux.HandleFunc("/", s.handleIndex)
-	mux.HandleFunc("/api/devices", s.handleDevices)
-	mux.HandleFunc("/bind", s.handleBind)
-	mux.HandleFunc("/unbind", s.handleUnbind)
-	mux.HandleFunc("/events", s.handleEvents)
+	mux.HandleFunc("/api/devices", s.requireTrustedPeer(s.handleDevices))
+	mux.HandleFunc("/bind", s.requireTrustedPeer(s.handleBind))
+	mux.HandleFunc("/unbind", s.requireTrustedPeer(s.handleUnbind))
+	mux.HandleFunc("/events", s.requireTrustedPeer(s.handleEvents))
+	mux.HandleFunc("/api/remotes", s.requireTrustedPeer(s.handleRemotes))
+	mux.HandleFunc("/attach", s.requireTrustedPeer(s.handleAttach))
+	mux.HandleFunc("/detach", s.requireTrustedPeer(s.handleDetach))
+	mux.HandleFunc("/events/remote", s.requireTrustedPeer(s.handleRemoteEvents))
+	mux.HandleFunc("/api/rules", s.requireTrustedPeer(s.handleRules))
+	mux.HandleFunc("/events/rules", s.requireTrustedPeer(s.handleRuleEvents))
 
 	// Serve static files
 	mux.Handle("/static/", http.FileServer(http.FS(staticFiles)))
@@ -584,6 +686,15 @@ func (s *Server) Run(ctx context.Context) error {
 		ReadTimeout:  15 * time.Second,
 		WriteTimeout: 15 * time.Second,
 		IdleTimeout:  60 * time.Second,
+		TLSConfig: &tls.Config{
+			Certificates: []tls.Certificate{s.identity.Certificate},
+			// The cert is only requested, not required, at the handshake
+			// level: "/" and "/static/" must stay reachable from a plain
+			// browser with no imported identity. requireTrustedPeer is what
+			// actually enforces mTLS, rejecting API/bind/unbind/events
+			// requests that arrive with no certificate or an untrusted one.
+			ClientAuth: tls.RequestClientCert,
+		},
 	}
 
 	// Start polling in background
@@ -594,11 +705,16 @@ func (s *Server) Run(ctx context.Context) error {
 	fmt.Printf("\nðŸš€ usbctl v%s server started successfully!\n", Version)
 	fmt.Printf("ðŸ“¡ Server: %s\n", addr)
 	fmt.Printf("ðŸŒ Web interface URLs:\n")
-	fmt.Printf("   http://localhost:%d\n", s.config.Port)
+	fmt.Printf("   https://localhost:%d\n", s.config.Port)
 	if localIP != "localhost" {
-		fmt.Printf("   http://%s:%d\n", localIP, s.config.Port)
+		fmt.Printf("   https://%s:%d\n", localIP, s.config.Port)
 	}
 	fmt.Printf("ðŸ“Š Status: Ready for connections\n")
+	fmt.Printf("Device ID: %s\n", s.identity.DeviceID)
+	fmt.Printf("Note: the Devices/Remotes/Rules tabs and their bind/unbind/attach/detach actions\n")
+	fmt.Printf("require a browser client certificate whose device ID has been added via\n")
+	fmt.Printf("`usbctl trust <id>`; usbctl has no browser-import helper yet, so import the\n")
+	fmt.Printf("peer's identity-cert.pem/identity-key.pem pair into the browser by hand to use them.\n")
 	fmt.Printf("\nâš ï¸  Press Ctrl+C to stop the server gracefully\n\n")
 
 	// Graceful shutdown
@@ -609,7 +725,7 @@ func (s *Server) Run(ctx context.Context) error {
 		server.Shutdown(shutdownCtx)
 	}()
 
-	if err := server.ListenAndServe(); err != http.ErrServerClosed {
+	if err := server.ListenAndServeTLS("", ""); err != http.ErrServerClosed {
 		return fmt.Errorf("server error: %w", err)
 	}
 
@@ -632,11 +748,19 @@ func printUsage() {
 	fmt.Printf("usbctl v%s - USB/IP Device Web Manager\n", Version)
 	fmt.Printf("Author: %s\n\n", Author)
 	fmt.Println("Usage: usbctl [OPTIONS]")
+	fmt.Println("       usbctl id")
+	fmt.Println("       usbctl trust <device-id>")
 	fmt.Println("\nOptions:")
 	fmt.Printf("  -p, --port PORT        Server port (default: %d)\n", DefaultPort)
 	fmt.Printf("  -b, --bind ADDRESS     Bind address (default: %s)\n", DefaultBind)
 	fmt.Println("  -i, --interval SEC     Polling interval (default: 3)")
 	fmt.Println("  -c, --config PATH      Configuration file path")
+	fmt.Printf("      --backend NAME     USB/IP backend to use (default: %s)\n", defaultBackendName())
+	fmt.Println("      --log_file PATH    Audit log file path (JSON lines; empty disables)")
+	fmt.Println("      --log_max_size MB  Audit log max size before rotating")
+	fmt.Println("      --log_max_backups N  Audit log max rotated files to keep")
+	fmt.Println("      --log_max_age DAYS Audit log max age before deletion")
+	fmt.Println("      --log_compress     Compress rotated audit logs")
 	fmt.Println("  -v, --verbose          Enable verbose logging")
 	fmt.Println("      --version          Show version")
 	fmt.Println("      --help             Show this help")
@@ -644,6 +768,8 @@ func printUsage() {
 	fmt.Println("  usbctl                 # Start web server")
 	fmt.Println("  usbctl -p 8080         # Start on port 8080")
 	fmt.Println("  usbctl -v              # Start with verbose logging")
+	fmt.Println("  usbctl id              # Print this installation's device ID")
+	fmt.Println("  usbctl trust AIR6LPZ-7K4PTTV-...  # Trust a peer's device ID")
 }
 
 func main() {
@@ -657,6 +783,12 @@ func main() {
 		intervalL  = flag.Int("interval", 0, "Polling interval in seconds")
 		configPath = flag.String("c", "", "Configuration file path")
 		configLong = flag.String("config", "", "Configuration file path")
+		backend    = flag.String("backend", "", "USB/IP backend to use")
+		logFile    = flag.String("log_file", "", "Audit log file path")
+		logMaxSize = flag.Int("log_max_size", 0, "Audit log max size in MB before rotating")
+		logMaxBackups = flag.Int("log_max_backups", 0, "Audit log max rotated files to keep")
+		logMaxAge  = flag.Int("log_max_age", 0, "Audit log max age in days before deletion")
+		logCompress = flag.Bool("log_compress", false, "Compress rotated audit logs")
 		verbose    = flag.Bool("v", false, "Enable verbose logging")
 		verboseLong= flag.Bool("verbose", false, "Enable verbose logging")
 		version    = flag.Bool("version", false, "Show version")
@@ -710,6 +842,25 @@ func main() {
 
 	cfg.ConfigPath = cfgPath
 
+	// Identity and trust subcommands: `usbctl id`, `usbctl trust <id>`
+	if flag.NArg() > 0 {
+		switch flag.Arg(0) {
+		case "id":
+			if err := runIDCommand(cfg); err != nil {
+				log.Fatalf("%v", err)
+			}
+			return
+		case "trust":
+			if flag.NArg() < 2 {
+				log.Fatalf("usage: usbctl trust <device-id>")
+			}
+			if err := runTrustCommand(cfg, flag.Arg(1)); err != nil {
+				log.Fatalf("%v", err)
+			}
+			return
+		}
+	}
+
 	// Override with command-line flags
 	if *port != 0 {
 		cfg.Port = *port
@@ -723,10 +874,34 @@ func main() {
 	if *verbose {
 		cfg.VerboseLog = true
 	}
+	if *backend != "" {
+		cfg.Backend = *backend
+	}
+	if *logFile != "" {
+		cfg.LogFile = *logFile
+	}
+	if *logMaxSize != 0 {
+		cfg.LogMaxSizeMB = *logMaxSize
+	}
+	if *logMaxBackups != 0 {
+		cfg.LogMaxBackups = *logMaxBackups
+	}
+	if *logMaxAge != 0 {
+		cfg.LogMaxAgeDays = *logMaxAge
+	}
+	if *logCompress {
+		cfg.LogCompress = true
+	}
 
 	// Create server
 	server := NewServer(cfg)
 
+	identity, err := LoadOrCreateIdentity(identityDir(cfg))
+	if err != nil {
+		log.Fatalf("Failed to load identity: %v", err)
+	}
+	server.identity = identity
+
 	// Setup signal handling for graceful shutdown
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()